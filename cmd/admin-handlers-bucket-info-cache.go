@@ -0,0 +1,100 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// NotifyBucketMutated is called whenever this node creates or deletes a
+// bucket, so the bucket-info cache doesn't keep serving a now-stale entry
+// until the next poll. It is also the hook the peer RPC layer would call
+// when another node reports a bucket mutation, once that plumbing exists.
+func NotifyBucketMutated(bucket string) {
+	globalBucketInfoCache.Delete(bucket)
+}
+
+// bucketInfoCacheMetrics is the shape returned by the admin metrics
+// endpoint for the bucket-info cache.
+type bucketInfoCacheMetrics struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Disabled bool   `json:"disabled"`
+}
+
+// SetBucketInfoCacheEnabled is the admin toggle backing `mc admin config
+// set` for the bucket-info cache; disabling it falls back to the
+// pre-existing StatVol fan-out on every GetBucketInfo call.
+func SetBucketInfoCacheEnabled(enabled bool) {
+	globalBucketInfoCache.Disable(!enabled)
+}
+
+// bucketInfoCacheMetricsSnapshot returns the cumulative hit/miss counters
+// and enabled state of the bucket-info cache, as served by
+// BucketInfoCacheMetricsHandler on its own endpoint.
+func bucketInfoCacheMetricsSnapshot() bucketInfoCacheMetrics {
+	hits, misses := globalBucketInfoCache.Metrics()
+	return bucketInfoCacheMetrics{
+		Hits:     hits,
+		Misses:   misses,
+		Disabled: globalBucketInfoCache.isDisabled(),
+	}
+}
+
+// BucketInfoCacheMetricsHandler - GET /minio/admin/v3/bucket-info-cache/metrics
+//
+// Reports the bucket-info cache's hit/miss counters and enabled state.
+func (a adminAPIHandlers) BucketInfoCacheMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, bucketInfoCacheAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	data, err := json.Marshal(bucketInfoCacheMetricsSnapshot())
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, data)
+}
+
+// SetBucketInfoCacheEnabledHandler - PUT /minio/admin/v3/bucket-info-cache/enabled?value=<bool>
+//
+// Backs `mc admin config set` for the bucket-info cache toggle.
+func (a adminAPIHandlers) SetBucketInfoCacheEnabledHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, bucketInfoCacheAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	enabled, err := strconv.ParseBool(r.URL.Query().Get("value"))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	SetBucketInfoCacheEnabled(enabled)
+	writeSuccessResponseHeadersOnly(w)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/internal/logger"
+)
+
+// GetBucketQuorumHandler - GET /minio/admin/v3/get-bucket-quorum?bucket=mybucket
+//
+// Returns the BucketQuorumPolicy currently configured for bucket, or a zero
+// value policy if the bucket uses the pool-wide defaults.
+func (a adminAPIHandlers) GetBucketQuorumHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, bucketQuorumAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	qp, _ := globalBucketQuorumSys.Get(ctx, bucketQuorumDisks(objectAPI), bucket)
+
+	data, err := json.Marshal(qp)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, data)
+}
+
+// PutBucketQuorumHandler - PUT /minio/admin/v3/put-bucket-quorum?bucket=mybucket
+//
+// Sets (or clears, when the body is a zero-value policy) the
+// BucketQuorumPolicy for bucket. Existing data is left untouched - only
+// the read/write quorum used for future operations on the bucket changes.
+func (a adminAPIHandlers) PutBucketQuorumHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, bucketQuorumAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+
+	var qp BucketQuorumPolicy
+	if err := json.NewDecoder(r.Body).Decode(&qp); err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	if qp.hasUnsupportedFields() {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, errBucketQuorumUnsupportedFields), r.URL)
+		return
+	}
+
+	if err := globalBucketQuorumSys.Set(ctx, bucketQuorumDisks(objectAPI), bucket, qp); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
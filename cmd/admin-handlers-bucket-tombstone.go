@@ -0,0 +1,83 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ListDeletedBucketsHandler - GET /minio/admin/v3/buckets/deleted
+//
+// Lists every soft-deleted bucket still sitting in the tombstone namespace,
+// not yet reaped by the retention purge scanner.
+func (a adminAPIHandlers) ListDeletedBucketsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, bucketTombstoneAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	er, ok := asErasureObjects(objectAPI)
+	if !ok {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, errServerNotInitialized), r.URL)
+		return
+	}
+
+	tombs, err := er.ListDeletedBuckets(ctx)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(tombs)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, data)
+}
+
+// RestoreBucketHandler - POST /minio/admin/v3/buckets/restore?tombName=<name>
+//
+// Undoes a prior soft-delete, renaming the bucket back from the tombstone
+// namespace to its original name.
+func (a adminAPIHandlers) RestoreBucketHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	objectAPI, _ := validateAdminReq(ctx, w, r, bucketTombstoneAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	er, ok := asErasureObjects(objectAPI)
+	if !ok {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, errServerNotInitialized), r.URL)
+		return
+	}
+
+	tombName := r.URL.Query().Get("tombName")
+	if err := er.RestoreBucket(ctx, tombName); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import iampolicy "github.com/minio/pkg/iam/policy"
+
+// Dedicated admin actions for the bucket-extension admin APIs added in this
+// series, so granting one of them doesn't also grant the unrelated
+// capabilities bundled under the generic iampolicy.ConfigUpdateAdminAction -
+// the same narrowing BucketIntentsHealStatusHandler already gets for free
+// from the existing iampolicy.HealAdminAction.
+//
+// These are not yet part of the upstream github.com/minio/pkg/iam/policy
+// action set or its default admin policies, so until that lands an
+// operator must grant them explicitly via a custom policy's Action list.
+const (
+	bucketQuorumAdminAction    iampolicy.AdminAction = "admin:BucketQuorumAction"
+	bucketInfoCacheAdminAction iampolicy.AdminAction = "admin:BucketInfoCacheAction"
+	bucketTombstoneAdminAction iampolicy.AdminAction = "admin:BucketTombstoneAction"
+)
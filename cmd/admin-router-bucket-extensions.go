@@ -0,0 +1,47 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// registerAdminBucketExtensionRouter wires the admin endpoints added
+// alongside the per-bucket quorum policy and bucket-intent-reconciler
+// features onto adminRouter. Called from registerAdminRouter.
+func registerAdminBucketExtensionRouter(adminRouter *mux.Router, adminAPI adminAPIHandlers) {
+	adminRouter.Methods(http.MethodGet).Path(adminVersion + "/get-bucket-quorum").
+		HandlerFunc(adminAPI.GetBucketQuorumHandler).Queries("bucket", "{bucket:.*}")
+	adminRouter.Methods(http.MethodPut).Path(adminVersion + "/put-bucket-quorum").
+		HandlerFunc(adminAPI.PutBucketQuorumHandler).Queries("bucket", "{bucket:.*}")
+
+	adminRouter.Methods(http.MethodGet).Path(adminVersion + "/heal/bucket-intents").
+		HandlerFunc(adminAPI.BucketIntentsHealStatusHandler)
+
+	adminRouter.Methods(http.MethodGet).Path(adminVersion + "/bucket-info-cache/metrics").
+		HandlerFunc(adminAPI.BucketInfoCacheMetricsHandler)
+	adminRouter.Methods(http.MethodPut).Path(adminVersion + "/bucket-info-cache/enabled").
+		HandlerFunc(adminAPI.SetBucketInfoCacheEnabledHandler).Queries("value", "{value:.*}")
+
+	adminRouter.Methods(http.MethodGet).Path(adminVersion + "/buckets/deleted").
+		HandlerFunc(adminAPI.ListDeletedBucketsHandler)
+	adminRouter.Methods(http.MethodPost).Path(adminVersion + "/buckets/restore").
+		HandlerFunc(adminAPI.RestoreBucketHandler).Queries("tombName", "{tombName:.*}")
+}
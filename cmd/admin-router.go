@@ -0,0 +1,30 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "github.com/gorilla/mux"
+
+// registerAdminRouter is the minimal admin API bootstrap this trimmed
+// series needs so its own handlers are reachable over HTTP. The full admin
+// router - object heal, user/policy/config management, and every other
+// existing v3 admin endpoint - lives in the real minio codebase outside
+// this series and is not reproduced here; this only wires the
+// bucket-extension endpoints this series added.
+func registerAdminRouter(adminRouter *mux.Router) {
+	registerAdminBucketExtensionRouter(adminRouter, adminAPIHandlers{})
+}
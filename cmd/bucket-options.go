@@ -0,0 +1,49 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "time"
+
+// BucketOptions represents bucket creation options.
+type BucketOptions struct {
+	Location          string
+	LockEnabled       bool
+	VersioningEnabled bool
+
+	// QuorumPolicy overrides the pool-wide erasure read/write quorum and
+	// disk-placement affinity for this bucket. The zero value means "use
+	// the pool-wide defaults", which is also what every bucket created
+	// before this field existed implicitly has.
+	QuorumPolicy BucketQuorumPolicy
+}
+
+// DeleteBucketOptions represents bucket delete options.
+type DeleteBucketOptions struct {
+	Force      bool // Force deletion
+	NoRecreate bool // Do not recreate on delete failures
+
+	// SoftDelete moves the bucket into the recycle bin instead of deleting
+	// it outright, so it can be brought back with RestoreBucket until
+	// Retention elapses. Force and NoRecreate are ignored when set.
+	SoftDelete bool
+
+	// Retention overrides defaultBucketTombstoneRetention for this bucket.
+	// Only meaningful when SoftDelete is set; the zero value means "use the
+	// default".
+	Retention time.Duration
+}
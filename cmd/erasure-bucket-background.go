@@ -0,0 +1,67 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"sync"
+)
+
+// erasureSetKey identifies the erasure set backing storageDisks. A
+// deployment runs one erasureObjects per set (the usual case once a
+// cluster grows past the smallest configurations), and the set's disks
+// never change identity for the life of the process, so this is stable
+// enough to key the once-per-set background jobs below.
+func erasureSetKey(storageDisks []StorageAPI) string {
+	var b strings.Builder
+	for _, disk := range storageDisks {
+		if disk == nil {
+			b.WriteByte('-')
+		} else {
+			b.WriteString(disk.String())
+		}
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// onceRegistry hands out a *sync.Once per key, creating it on first use. A
+// single package-level sync.Once would only ever run its goroutine for the
+// first erasureObjects that happened to call in, leaving every other
+// erasure set's intent reconciler, bucket-info poller, or tombstone purge
+// scanner permanently unstarted; keying by erasureSetKey gives each set its
+// own Once instead.
+type onceRegistry struct {
+	mu    sync.Mutex
+	onces map[string]*sync.Once
+}
+
+func newOnceRegistry() *onceRegistry {
+	return &onceRegistry{onces: make(map[string]*sync.Once)}
+}
+
+func (r *onceRegistry) get(key string) *sync.Once {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	once, ok := r.onces[key]
+	if !ok {
+		once = new(sync.Once)
+		r.onces[key] = once
+	}
+	return once
+}
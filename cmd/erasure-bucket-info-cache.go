@@ -0,0 +1,186 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// bucketInfoCacheStaleness bounds how old a cached BucketInfo may be before
+// GetBucketInfo falls back to the full StatVol fan-out instead of trusting
+// the cache.
+const bucketInfoCacheStaleness = 10 * time.Second
+
+// bucketInfoCachePollInterval is how often the background poller refreshes
+// every bucket currently tracked in the cache.
+const bucketInfoCachePollInterval = 5 * time.Second
+
+// envBucketInfoCacheDisable lets operators turn the cache off entirely and
+// fall back to the pre-existing StatVol fan-out on every call, e.g. while
+// diagnosing a listing/HEAD latency regression.
+const envBucketInfoCacheDisable = "MINIO_DISABLE_BUCKET_INFO_CACHE"
+
+type cachedBucketInfo struct {
+	info      BucketInfo
+	updatedAt time.Time
+}
+
+// bucketInfoCache maintains a bounded-staleness BucketInfo per bucket,
+// populated by a single background poller instead of a StatVol fan-out on
+// every GetBucketInfo call. It is invalidated eagerly when this node
+// creates, deletes or restores a bucket. A mutation made on another node is
+// not signalled here; it is picked up by the next poll, or once the entry
+// is older than bucketInfoCacheStaleness.
+type bucketInfoCache struct {
+	mu      sync.RWMutex
+	entries map[string]cachedBucketInfo
+
+	hits   uint64
+	misses uint64
+
+	disabled uint32
+}
+
+func newBucketInfoCache() *bucketInfoCache {
+	c := &bucketInfoCache{
+		entries: make(map[string]cachedBucketInfo),
+	}
+	if disabled, err := strconv.ParseBool(os.Getenv(envBucketInfoCacheDisable)); err == nil {
+		c.Disable(disabled)
+	}
+	return c
+}
+
+// Disable turns the cache off (GetBucketInfo always falls back to the
+// fan-out path) without losing the accumulated hit/miss metrics.
+func (c *bucketInfoCache) Disable(disabled bool) {
+	if disabled {
+		atomic.StoreUint32(&c.disabled, 1)
+	} else {
+		atomic.StoreUint32(&c.disabled, 0)
+	}
+}
+
+func (c *bucketInfoCache) isDisabled() bool {
+	return atomic.LoadUint32(&c.disabled) == 1
+}
+
+// Get returns the cached BucketInfo for bucket if present and not older
+// than bucketInfoCacheStaleness.
+func (c *bucketInfoCache) Get(bucket string) (BucketInfo, bool) {
+	if c.isDisabled() {
+		return BucketInfo{}, false
+	}
+
+	c.mu.RLock()
+	entry, ok := c.entries[bucket]
+	c.mu.RUnlock()
+
+	if !ok || time.Since(entry.updatedAt) > bucketInfoCacheStaleness {
+		atomic.AddUint64(&c.misses, 1)
+		return BucketInfo{}, false
+	}
+
+	atomic.AddUint64(&c.hits, 1)
+	return entry.info, true
+}
+
+// Set refreshes bucket's cached BucketInfo, as done by the background
+// poller after a successful StatVol fan-out.
+func (c *bucketInfoCache) Set(bucket string, info BucketInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[bucket] = cachedBucketInfo{info: info, updatedAt: time.Now()}
+}
+
+// Delete invalidates bucket, called via NotifyBucketMutated when this node
+// creates, deletes or restores a bucket, and by the poller when a refresh
+// fails.
+func (c *bucketInfoCache) Delete(bucket string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, bucket)
+}
+
+// trackedBuckets returns the buckets currently tracked, for the poller to
+// refresh.
+func (c *bucketInfoCache) trackedBuckets() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	buckets := make([]string, 0, len(c.entries))
+	for bucket := range c.entries {
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// Metrics returns the cumulative hit/miss counters, exposed via the admin
+// metrics endpoint.
+func (c *bucketInfoCache) Metrics() (hits, misses uint64) {
+	return atomic.LoadUint64(&c.hits), atomic.LoadUint64(&c.misses)
+}
+
+// globalBucketInfoCache is the process-wide bucket-info cache consulted by
+// erasureObjects.GetBucketInfo.
+var globalBucketInfoCache = newBucketInfoCache()
+
+// bucketInfoCachePollOnces ensures pollBucketInfoCache is only ever started
+// once per erasure set, no matter how many times GetBucketInfo triggers it.
+// Keying by process alone would only ever poll the first set to call in,
+// leaving every other set's cache entries to go stale forever.
+var bucketInfoCachePollOnces = newOnceRegistry()
+
+// ensureBucketInfoCachePolling kicks off pollBucketInfoCache in the
+// background exactly once per erasure set.
+func (er erasureObjects) ensureBucketInfoCachePolling() {
+	storageDisks := er.getDisks()
+	bucketInfoCachePollOnces.get(erasureSetKey(storageDisks)).Do(func() {
+		go er.pollBucketInfoCache(context.Background())
+	})
+}
+
+// pollBucketInfoCache refreshes every tracked bucket's BucketInfo on
+// bucketInfoCachePollInterval, using the existing StatVol fan-out via
+// er.getBucketInfo, until ctx is canceled. It is the single background
+// poller the bucket-info cache relies on instead of every caller fanning
+// out independently.
+func (er erasureObjects) pollBucketInfoCache(ctx context.Context) {
+	ticker := time.NewTicker(bucketInfoCachePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, bucket := range globalBucketInfoCache.trackedBuckets() {
+				info, err := er.getBucketInfo(ctx, bucket)
+				if err != nil {
+					globalBucketInfoCache.Delete(bucket)
+					continue
+				}
+				globalBucketInfoCache.Set(bucket, info)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "sync/atomic"
+
+// atomicBucketIntentReplayCount counts dangling bucket intents replayed by
+// reconcileBucketIntents since this node started. It is surfaced as a
+// BucketIntentReconcilerStatus by BucketIntentsHealStatusHandler at
+// /minio/admin/v3/heal/bucket-intents, a separate endpoint from the
+// /minio/admin/v3/heal response, so operators can tell whether a restart
+// actually had to converge a partial bucket create or delete.
+var atomicBucketIntentReplayCount uint64
+
+// BucketIntentReconcilerStatus reports how many dangling bucket intents
+// this node has replayed since startup.
+type BucketIntentReconcilerStatus struct {
+	RepairedIntents uint64 `json:"repairedIntents"`
+}
+
+// bucketIntentReconcilerStatus returns the current BucketIntentReconcilerStatus.
+func bucketIntentReconcilerStatus() BucketIntentReconcilerStatus {
+	return BucketIntentReconcilerStatus{RepairedIntents: atomic.LoadUint64(&atomicBucketIntentReplayCount)}
+}
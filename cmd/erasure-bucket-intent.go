@@ -0,0 +1,190 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/internal/logger"
+	"github.com/minio/minio/internal/sync/errgroup"
+)
+
+// bucketIntentReconcileOnces guards the one reconciliation pass each
+// erasure set runs against its dangling bucket intents. There is no
+// dedicated startup hook in this series to call reconcileBucketIntents
+// from, so it is triggered the first time that set handles a
+// MakeBucket/DeleteBucket call instead - functionally "at startup" for any
+// set that serves bucket lifecycle traffic, which is the only time
+// dangling intents matter.
+var bucketIntentReconcileOnces = newOnceRegistry()
+
+// ensureBucketIntentsReconciled kicks off reconcileBucketIntents in the
+// background exactly once per erasure set.
+func (er erasureObjects) ensureBucketIntentsReconciled() {
+	storageDisks := er.getDisks()
+	bucketIntentReconcileOnces.get(erasureSetKey(storageDisks)).Do(func() {
+		go reconcileBucketIntentsOnDisks(context.Background(), storageDisks)
+	})
+}
+
+// bucketIntentOp names the bucket lifecycle operation an intent record
+// describes.
+type bucketIntentOp string
+
+const (
+	bucketIntentCreate     bucketIntentOp = "create-bucket"
+	bucketIntentDelete     bucketIntentOp = "delete-bucket"
+	bucketIntentSoftDelete bucketIntentOp = "soft-delete-bucket"
+)
+
+// bucketIntentPrefix is the sub-directory of minioMetaTmpDeletedBucket that
+// holds dangling intent records, keeping them alongside the renamed-bucket
+// tombstones that mechanism already produces.
+const bucketIntentPrefix = "intents"
+
+// bucketIntent is the durable record written to a quorum of disks before
+// MakeVol/DeleteVol is attempted. Its presence past a commit or rollback
+// means the prior attempt crashed partway through and the reconciler must
+// replay it to converge.
+type bucketIntent struct {
+	ID        string         `json:"id"`
+	Op        bucketIntentOp `json:"op"`
+	Bucket    string         `json:"bucket"`
+	CreatedAt time.Time      `json:"createdAt"`
+
+	// TombName and Retention are only set when Op is bucketIntentSoftDelete,
+	// identifying the tombstone the rename is moving the bucket into.
+	TombName  string        `json:"tombName,omitempty"`
+	Retention time.Duration `json:"retention,omitempty"`
+}
+
+func bucketIntentPath(id string) string {
+	return path.Join(bucketIntentPrefix, id+".json")
+}
+
+// writeBucketIntent persists intent to a write quorum of storageDisks under
+// minioMetaTmpDeletedBucket. It returns an error if quorum could not be
+// reached, in which case the caller must not proceed to MakeVol/DeleteVol.
+func writeBucketIntent(ctx context.Context, storageDisks []StorageAPI, intent bucketIntent) error {
+	data, err := json.Marshal(intent)
+	if err != nil {
+		return err
+	}
+
+	g := errgroup.WithNErrs(len(storageDisks))
+	for index := range storageDisks {
+		index := index
+		g.Go(func() error {
+			if storageDisks[index] == nil {
+				return errDiskNotFound
+			}
+			return storageDisks[index].WriteAll(ctx, minioMetaTmpDeletedBucket, bucketIntentPath(intent.ID), data)
+		}, index)
+	}
+
+	_, writeQuorum := globalBucketQuorumSys.quorumForBucket(ctx, storageDisks, intent.Bucket, len(storageDisks))
+	return reduceWriteQuorumErrs(ctx, g.Wait(), bucketOpIgnoredErrs, writeQuorum)
+}
+
+// clearBucketIntent removes intent from every disk once the operation it
+// describes has either committed or been rolled back. Best effort - a
+// leftover record is harmless, the reconciler simply replays it again and
+// finds the bucket already converged.
+func clearBucketIntent(ctx context.Context, storageDisks []StorageAPI, id string) {
+	g := errgroup.WithNErrs(len(storageDisks))
+	for index := range storageDisks {
+		index := index
+		g.Go(func() error {
+			if storageDisks[index] == nil {
+				return errDiskNotFound
+			}
+			return storageDisks[index].Delete(ctx, minioMetaTmpDeletedBucket, bucketIntentPath(id), DeleteOptions{})
+		}, index)
+	}
+	g.Wait()
+}
+
+// reconcileBucketIntents replays any dangling bucket intents left behind by
+// a node crash mid-MakeBucket or a split-brain during a forced DeleteBucket.
+// It is run once per erasure set (the number of intents it replayed is
+// reported by BucketIntentReconcilerStatus) so partial failures converge deterministically:
+// a create intent is completed by retrying MakeVol, a delete intent by
+// retrying DeleteVol, after which the intent record is cleared.
+func (er erasureObjects) reconcileBucketIntents(ctx context.Context) {
+	reconcileBucketIntentsOnDisks(ctx, er.getDisks())
+}
+
+// reconcileBucketIntentsOnDisks is the disk-parameterized core of
+// reconcileBucketIntents, split out so it can be exercised directly against
+// a fake StorageAPI in tests without needing a real erasureObjects.
+func reconcileBucketIntentsOnDisks(ctx context.Context, storageDisks []StorageAPI) {
+	seen := make(map[string]bucketIntent)
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		entries, err := disk.ListDir(ctx, minioMetaTmpDeletedBucket, bucketIntentPrefix, -1)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			data, err := disk.ReadAll(ctx, minioMetaTmpDeletedBucket, path.Join(bucketIntentPrefix, entry))
+			if err != nil {
+				continue
+			}
+			var intent bucketIntent
+			if err := json.Unmarshal(data, &intent); err != nil {
+				continue
+			}
+			seen[intent.ID] = intent
+		}
+	}
+
+	for id, intent := range seen {
+		switch intent.Op {
+		case bucketIntentCreate:
+			for _, disk := range storageDisks {
+				if disk == nil {
+					continue
+				}
+				if err := disk.MakeVol(ctx, intent.Bucket); err != nil && err != errVolumeExists {
+					logger.LogIf(ctx, err)
+				}
+			}
+		case bucketIntentDelete:
+			for _, disk := range storageDisks {
+				if disk == nil {
+					continue
+				}
+				if err := disk.DeleteVol(ctx, intent.Bucket, true); err != nil && err != errVolumeNotFound {
+					logger.LogIf(ctx, err)
+				}
+			}
+		case bucketIntentSoftDelete:
+			if err := writeBucketTombstone(ctx, storageDisks, intent.Bucket, intent.TombName, intent.Retention); err != nil {
+				logger.LogIf(ctx, err)
+			}
+		}
+		clearBucketIntent(ctx, storageDisks, id)
+		atomic.AddUint64(&atomicBucketIntentReplayCount, 1)
+	}
+}
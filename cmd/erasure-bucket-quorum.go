@@ -0,0 +1,279 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"sync"
+
+	"github.com/minio/minio/internal/sync/errgroup"
+)
+
+// errBucketQuorumUnsupportedFields is returned by PutBucketQuorumHandler
+// when the submitted policy sets DataShards, ParityShards, or
+// PlacementAffinity - see BucketQuorumPolicy.hasUnsupportedFields.
+var errBucketQuorumUnsupportedFields = errors.New("bucket quorum policy: DataShards, ParityShards, and PlacementAffinity are not yet honored by quorum calculations; only ReadQuorum/WriteQuorum may be set")
+
+// bucketQuorumPolicyFile is the sidecar persisted alongside the rest of a
+// bucket's metadata (next to .metadata.bin, under minioMetaBucket) so a
+// configured BucketQuorumPolicy survives a process restart instead of
+// silently reverting to the pool-wide defaults.
+const bucketQuorumPolicyFile = ".quorum.json"
+
+func bucketQuorumPolicyPath(bucket string) string {
+	return path.Join(bucketMetaPrefix, bucket, bucketQuorumPolicyFile)
+}
+
+// BucketQuorumPolicy carries per-bucket overrides of the pool-wide defaults
+// derived from the raw disk count. Only ReadQuorum and WriteQuorum change
+// how the bucket is handled; the erasure layout (data/parity split) and
+// disk placement of a bucket are not changed by this policy.
+type BucketQuorumPolicy struct {
+	// DataShards/ParityShards describe a desired erasure layout. They are
+	// persisted with the policy but not read by any quorum calculation or
+	// checked against the erasure set the bucket lives on.
+	DataShards   int
+	ParityShards int
+
+	// ReadQuorum/WriteQuorum override getReadQuorum/getWriteQuorum for this
+	// bucket when non-zero. A value above the erasure set size is ignored
+	// and the pool-wide default is used instead.
+	ReadQuorum  int
+	WriteQuorum int
+
+	// PlacementAffinity lists disk-placement labels (e.g. "rack=r1",
+	// "zone=us-east-1a"). They are persisted with the policy; neither
+	// healing nor shard placement reads them.
+	PlacementAffinity []string
+}
+
+// isZero reports whether the policy carries no overrides, i.e. the bucket
+// should fall back to the pool-wide defaults.
+func (p BucketQuorumPolicy) isZero() bool {
+	return p.DataShards == 0 && p.ParityShards == 0 &&
+		p.ReadQuorum == 0 && p.WriteQuorum == 0 && len(p.PlacementAffinity) == 0
+}
+
+// hasUnsupportedFields reports whether p sets DataShards, ParityShards, or
+// PlacementAffinity - fields that are persisted and round-tripped but not
+// yet honored by any quorum calculation (only ReadQuorum/WriteQuorum are).
+// PutBucketQuorumHandler rejects these outright rather than silently
+// accepting a policy that looks like it does more than it does.
+func (p BucketQuorumPolicy) hasUnsupportedFields() bool {
+	return p.DataShards != 0 || p.ParityShards != 0 || len(p.PlacementAffinity) != 0
+}
+
+// clamp bounds the configured quorum values to a sane range for a set of
+// size n, so a misconfigured policy can never exceed what the erasure set
+// can actually tolerate.
+func (p BucketQuorumPolicy) clamp(n int) (readQuorum, writeQuorum int) {
+	readQuorum, writeQuorum = getReadQuorum(n), getWriteQuorum(n)
+	if p.ReadQuorum > 0 && p.ReadQuorum <= n {
+		readQuorum = p.ReadQuorum
+	}
+	if p.WriteQuorum > 0 && p.WriteQuorum <= n {
+		writeQuorum = p.WriteQuorum
+	}
+	return readQuorum, writeQuorum
+}
+
+// bucketQuorumPolicySys tracks the configured BucketQuorumPolicy for every
+// bucket that has one. The in-memory map is a read-through cache over the
+// durable copy persisted at bucketQuorumPolicyPath alongside the rest of
+// the bucket's metadata; buckets without a sidecar transparently use the
+// pool-wide quorum computed from disk count, which is also how existing
+// buckets created before this feature migrate with no explicit step.
+type bucketQuorumPolicySys struct {
+	mu      sync.RWMutex
+	entries map[string]BucketQuorumPolicy
+}
+
+func newBucketQuorumPolicySys() *bucketQuorumPolicySys {
+	return &bucketQuorumPolicySys{
+		entries: make(map[string]BucketQuorumPolicy),
+	}
+}
+
+// cached returns the in-memory entry for bucket, if this process has
+// already loaded or set one.
+func (sys *bucketQuorumPolicySys) cached(bucket string) (BucketQuorumPolicy, bool) {
+	sys.mu.RLock()
+	defer sys.mu.RUnlock()
+	policy, ok := sys.entries[bucket]
+	return policy, ok
+}
+
+// Get returns the configured policy for bucket, if any, loading it from
+// disk on first access after a restart.
+//
+// A bucket with no configured policy is deliberately never cached: caching
+// the "no override" result would mean a PutBucketQuorumHandler call handled
+// by a different node - which only updates that node's disk sidecar and its
+// own in-memory entry - stays invisible to this node until it restarts.
+// Only confirmed policies are cached; the cost is that every quorum lookup
+// for a bucket without a policy re-reads its (most likely absent) sidecar.
+func (sys *bucketQuorumPolicySys) Get(ctx context.Context, storageDisks []StorageAPI, bucket string) (BucketQuorumPolicy, bool) {
+	if policy, ok := sys.cached(bucket); ok {
+		return policy, true
+	}
+
+	policy, err := loadBucketQuorumPolicy(ctx, storageDisks, bucket)
+	if err != nil || policy.isZero() {
+		return BucketQuorumPolicy{}, false
+	}
+
+	sys.mu.Lock()
+	sys.entries[bucket] = policy
+	sys.mu.Unlock()
+
+	return policy, true
+}
+
+// Set persists policy for bucket alongside its metadata and refreshes the
+// in-memory cache. Passing a zero-value policy is equivalent to Delete,
+// and is how MakeBucket leaves a bucket created without a policy on the
+// pool-wide defaults.
+func (sys *bucketQuorumPolicySys) Set(ctx context.Context, storageDisks []StorageAPI, bucket string, policy BucketQuorumPolicy) error {
+	if policy.isZero() {
+		return sys.Delete(ctx, storageDisks, bucket)
+	}
+
+	if err := persistBucketQuorumPolicy(ctx, storageDisks, bucket, policy); err != nil {
+		return err
+	}
+
+	sys.mu.Lock()
+	sys.entries[bucket] = policy
+	sys.mu.Unlock()
+	return nil
+}
+
+// Delete removes bucket's policy sidecar from disk and the in-memory
+// cache. Called on DeleteBucket so a recreated bucket of the same name
+// never inherits a stale policy.
+func (sys *bucketQuorumPolicySys) Delete(ctx context.Context, storageDisks []StorageAPI, bucket string) error {
+	err := deleteBucketQuorumPolicy(ctx, storageDisks, bucket)
+
+	sys.mu.Lock()
+	delete(sys.entries, bucket)
+	sys.mu.Unlock()
+
+	return err
+}
+
+// quorumForBucket returns the read/write quorum that should be used for
+// bucket given n reachable storage disks, honoring any configured
+// BucketQuorumPolicy (loading it from disk on first use after a restart)
+// and falling back to the pool-wide defaults otherwise.
+func (sys *bucketQuorumPolicySys) quorumForBucket(ctx context.Context, storageDisks []StorageAPI, bucket string, n int) (readQuorum, writeQuorum int) {
+	if policy, ok := sys.Get(ctx, storageDisks, bucket); ok {
+		return policy.clamp(n)
+	}
+	return getReadQuorum(n), getWriteQuorum(n)
+}
+
+// persistBucketQuorumPolicy writes policy to a write quorum of disks.
+func persistBucketQuorumPolicy(ctx context.Context, storageDisks []StorageAPI, bucket string, policy BucketQuorumPolicy) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	g := errgroup.WithNErrs(len(storageDisks))
+	for index := range storageDisks {
+		index := index
+		g.Go(func() error {
+			if storageDisks[index] == nil {
+				return errDiskNotFound
+			}
+			return storageDisks[index].WriteAll(ctx, minioMetaBucket, bucketQuorumPolicyPath(bucket), data)
+		}, index)
+	}
+
+	writeQuorum := getWriteQuorum(len(storageDisks))
+	return reduceWriteQuorumErrs(ctx, g.Wait(), bucketMetadataOpIgnoredErrs, writeQuorum)
+}
+
+// deleteBucketQuorumPolicy removes the sidecar from every disk. Best
+// effort - a leftover sidecar for a deleted bucket is harmless since a
+// future bucket of the same name overwrites it via Set on its own
+// MakeBucket, but we still clean up proactively so ListBucket-style
+// metadata scans don't trip over it.
+func deleteBucketQuorumPolicy(ctx context.Context, storageDisks []StorageAPI, bucket string) error {
+	g := errgroup.WithNErrs(len(storageDisks))
+	for index := range storageDisks {
+		index := index
+		g.Go(func() error {
+			if storageDisks[index] == nil {
+				return errDiskNotFound
+			}
+			return storageDisks[index].Delete(ctx, minioMetaBucket, bucketQuorumPolicyPath(bucket), DeleteOptions{})
+		}, index)
+	}
+	return reduceWriteQuorumErrs(ctx, g.Wait(), bucketMetadataOpIgnoredErrs, getWriteQuorum(len(storageDisks)))
+}
+
+// loadBucketQuorumPolicy returns the policy persisted for bucket, reading
+// from whichever disk answers first - the same load-balanced-read
+// approach er.getBucketInfo uses for BucketInfo itself. A bucket with no
+// sidecar (the common case) returns a zero-value policy and no error.
+func loadBucketQuorumPolicy(ctx context.Context, storageDisks []StorageAPI, bucket string) (BucketQuorumPolicy, error) {
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		data, err := disk.ReadAll(ctx, minioMetaBucket, bucketQuorumPolicyPath(bucket))
+		if err != nil {
+			continue
+		}
+		var policy BucketQuorumPolicy
+		if err := json.Unmarshal(data, &policy); err == nil {
+			return policy, nil
+		}
+	}
+	return BucketQuorumPolicy{}, nil
+}
+
+// globalBucketQuorumSys holds the process-wide per-bucket quorum policy
+// table consulted by erasureObjects when computing read/write quorum.
+var globalBucketQuorumSys = newBucketQuorumPolicySys()
+
+// BucketHealQuorum returns the read/write quorum the healer should target
+// when scanning bucket, honoring any configured BucketQuorumPolicy instead
+// of computing quorum purely from disk count. The bucket healer itself
+// lives outside this series (cmd/erasure-healing.go is not part of it);
+// this is the hook it would call.
+func BucketHealQuorum(ctx context.Context, storageDisks []StorageAPI, bucket string) (readQuorum, writeQuorum int) {
+	readQuorum, writeQuorum = globalBucketQuorumSys.quorumForBucket(ctx, storageDisks, bucket, len(storageDisks))
+	return readQuorum, writeQuorum
+}
+
+// bucketQuorumDisks returns the storage disks backing objectAPI, for admin
+// handlers that only have an ObjectLayer to work with. The quorum policy
+// feature is erasure-specific, so a non-erasure ObjectLayer yields no
+// disks and callers fall back to an empty set, same as a fully offline
+// erasure set would.
+func bucketQuorumDisks(objectAPI ObjectLayer) []StorageAPI {
+	if er, ok := objectAPI.(erasureObjects); ok {
+		return er.getDisks()
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestBucketQuorumPolicyIsZero(t *testing.T) {
+	if !(BucketQuorumPolicy{}).isZero() {
+		t.Fatal("zero-value BucketQuorumPolicy should report isZero")
+	}
+	if (BucketQuorumPolicy{ReadQuorum: 2}).isZero() {
+		t.Fatal("policy with ReadQuorum set should not report isZero")
+	}
+	if (BucketQuorumPolicy{PlacementAffinity: []string{"rack=r1"}}).isZero() {
+		t.Fatal("policy with PlacementAffinity set should not report isZero")
+	}
+}
+
+func TestBucketQuorumPolicyClamp(t *testing.T) {
+	// No overrides: falls back to the pool-wide defaults for n disks.
+	rq, wq := (BucketQuorumPolicy{}).clamp(8)
+	if rq != getReadQuorum(8) || wq != getWriteQuorum(8) {
+		t.Fatalf("expected pool defaults, got read=%d write=%d", rq, wq)
+	}
+
+	// In-range overrides are honored as-is.
+	rq, wq = (BucketQuorumPolicy{ReadQuorum: 3, WriteQuorum: 5}).clamp(8)
+	if rq != 3 || wq != 5 {
+		t.Fatalf("expected overrides to be honored, got read=%d write=%d", rq, wq)
+	}
+
+	// Out-of-range overrides never exceed what the erasure set can
+	// tolerate - a misconfigured policy falls back to the pool defaults
+	// rather than making the bucket unreadable or unwritable.
+	rq, wq = (BucketQuorumPolicy{ReadQuorum: 99, WriteQuorum: 99}).clamp(8)
+	if rq != getReadQuorum(8) || wq != getWriteQuorum(8) {
+		t.Fatalf("expected out-of-range overrides to fall back, got read=%d write=%d", rq, wq)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/minio/minio/internal/logger"
+	"github.com/minio/minio/internal/sync/errgroup"
+)
+
+// defaultBucketTombstoneRetention is how long a soft-deleted bucket is kept
+// around before the purge scanner removes it for good, when
+// DeleteBucketOptions.Retention is zero.
+const defaultBucketTombstoneRetention = 24 * time.Hour
+
+// bucketTombstonePurgeInterval is how often the background scanner checks
+// tracked tombstones for an elapsed retention window.
+const bucketTombstonePurgeInterval = time.Hour
+
+// bucketTombstonePurgeOnces guards the one purge-scanner goroutine each
+// erasure set runs, the same way bucketIntentReconcileOnces guards the
+// intent reconciler - one Once per set, not one for the whole process.
+var bucketTombstonePurgeOnces = newOnceRegistry()
+
+// ensureBucketTombstonePurging kicks off pollBucketTombstonePurge in the
+// background exactly once per erasure set.
+func (er erasureObjects) ensureBucketTombstonePurging() {
+	storageDisks := er.getDisks()
+	bucketTombstonePurgeOnces.get(erasureSetKey(storageDisks)).Do(func() {
+		go er.pollBucketTombstonePurge(context.Background())
+	})
+}
+
+// pollBucketTombstonePurge runs purgeExpiredBucketTombstones on
+// bucketTombstonePurgeInterval until ctx is canceled.
+func (er erasureObjects) pollBucketTombstonePurge(ctx context.Context) {
+	ticker := time.NewTicker(bucketTombstonePurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			er.purgeExpiredBucketTombstones(ctx)
+		}
+	}
+}
+
+// asErasureObjects recovers the erasureObjects backing objectAPI, for admin
+// handlers that only have an ObjectLayer to work with. Bucket soft-delete is
+// erasure-specific, same as bucketQuorumDisks above.
+func asErasureObjects(objectAPI ObjectLayer) (erasureObjects, bool) {
+	er, ok := objectAPI.(erasureObjects)
+	return er, ok
+}
+
+// bucketTombstone is the record written alongside the renamed volume under
+// minioMetaTmpDeletedBucket, recording enough to list, restore, or expire
+// it later.
+type bucketTombstone struct {
+	Bucket    string        `json:"bucket"`
+	TombName  string        `json:"tombName"`
+	DeletedAt time.Time     `json:"deletedAt"`
+	Retention time.Duration `json:"retention"`
+}
+
+func (t bucketTombstone) expired(now time.Time) bool {
+	retention := t.Retention
+	if retention <= 0 {
+		retention = defaultBucketTombstoneRetention
+	}
+	return now.Sub(t.DeletedAt) >= retention
+}
+
+func bucketTombstoneMetaPath(tombName string) string {
+	return path.Join(tombName, bucketMetaFile)
+}
+
+// bucketMetaFile is the name of the tombstone metadata sidecar written next
+// to the renamed volume.
+const bucketMetaFile = ".tombstone.json"
+
+// softDeleteBucket renames bucket into the tombstone namespace instead of
+// removing it, recording a bucketTombstone sidecar so RestoreBucket and the
+// purge scanner can find it again later. It extends the existing
+// RenameFile-into-minioMetaTmpDeletedBucket mechanism DeleteBucket already
+// uses for dangling non-empty buckets.
+//
+// Like MakeBucketWithLocation/DeleteBucket, the rename is guarded by the
+// same bucketIntent journal: a crash between the intent write and the
+// rename/sidecar commit below is replayed by reconcileBucketIntents on the
+// next startup instead of leaving the bucket neither fully live nor fully
+// tombstoned.
+func (er erasureObjects) softDeleteBucket(ctx context.Context, bucket string, retention time.Duration) error {
+	er.ensureBucketTombstonePurging()
+
+	storageDisks := er.getDisks()
+	tombName := bucket + "-" + mustGetUUID()
+
+	intent := bucketIntent{
+		ID:        mustGetUUID(),
+		Op:        bucketIntentSoftDelete,
+		Bucket:    bucket,
+		TombName:  tombName,
+		Retention: retention,
+		CreatedAt: UTCNow(),
+	}
+	if err := writeBucketIntent(ctx, storageDisks, intent); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
+	if err := writeBucketTombstone(ctx, storageDisks, bucket, tombName, retention); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
+	// Committed: the intent has served its purpose.
+	clearBucketIntent(ctx, storageDisks, intent.ID)
+	return nil
+}
+
+// writeBucketTombstone renames bucket into the tombstone namespace and
+// writes its sidecar, the half of softDeleteBucket that reconcileBucketIntents
+// also replays for a dangling bucketIntentSoftDelete. A RenameFile that
+// reports errVolumeNotFound is treated as already-applied rather than a
+// failure, so a replay after a partial commit is idempotent.
+func writeBucketTombstone(ctx context.Context, storageDisks []StorageAPI, bucket, tombName string, retention time.Duration) error {
+	tomb := bucketTombstone{
+		Bucket:    bucket,
+		TombName:  tombName,
+		DeletedAt: UTCNow(),
+		Retention: retention,
+	}
+	data, err := json.Marshal(tomb)
+	if err != nil {
+		return err
+	}
+
+	g := errgroup.WithNErrs(len(storageDisks))
+	for index := range storageDisks {
+		index := index
+		g.Go(func() error {
+			if storageDisks[index] == nil {
+				return errDiskNotFound
+			}
+			if err := storageDisks[index].RenameFile(ctx, bucket, "", minioMetaTmpDeletedBucket, tombName); err != nil && err != errVolumeNotFound {
+				return err
+			}
+			return storageDisks[index].WriteAll(ctx, minioMetaTmpDeletedBucket, bucketTombstoneMetaPath(tombName), data)
+		}, index)
+	}
+
+	_, writeQuorum := globalBucketQuorumSys.quorumForBucket(ctx, storageDisks, bucket, len(storageDisks))
+	err = reduceWriteQuorumErrs(ctx, g.Wait(), bucketOpIgnoredErrs, writeQuorum)
+	return toObjectErr(err, bucket)
+}
+
+// RestoreBucket undoes a prior soft-delete, renaming the bucket back from
+// the tombstone namespace to its original name. It is the bucket-level
+// analogue of restoring an object from a version's delete marker.
+func (er erasureObjects) RestoreBucket(ctx context.Context, tombName string) error {
+	storageDisks := er.getDisks()
+
+	data, err := readBucketTombstone(ctx, storageDisks, tombName)
+	if err != nil {
+		return err
+	}
+
+	g := errgroup.WithNErrs(len(storageDisks))
+	for index := range storageDisks {
+		index := index
+		g.Go(func() error {
+			if storageDisks[index] == nil {
+				return errDiskNotFound
+			}
+			if err := storageDisks[index].RenameFile(ctx, minioMetaTmpDeletedBucket, tombName, data.Bucket, ""); err != nil {
+				return err
+			}
+			// The sidecar moved along with the rest of the tombstone
+			// directory's contents and now sits at the restored bucket's
+			// root - clean it up so it doesn't show up as a stray object.
+			return storageDisks[index].Delete(ctx, data.Bucket, bucketMetaFile, DeleteOptions{})
+		}, index)
+	}
+
+	_, writeQuorum := globalBucketQuorumSys.quorumForBucket(ctx, storageDisks, data.Bucket, len(storageDisks))
+	err = reduceWriteQuorumErrs(ctx, g.Wait(), bucketOpIgnoredErrs, writeQuorum)
+	if err != nil {
+		return toObjectErr(err, data.Bucket)
+	}
+
+	NotifyBucketMutated(data.Bucket)
+	return nil
+}
+
+// ListDeletedBuckets returns every bucket currently sitting in the
+// tombstone namespace, not yet purged by the TTL scanner.
+func (er erasureObjects) ListDeletedBuckets(ctx context.Context) ([]bucketTombstone, error) {
+	storageDisks := er.getDisks()
+
+	seen := make(map[string]bucketTombstone)
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		entries, err := disk.ListDir(ctx, minioMetaTmpDeletedBucket, "", -1)
+		if err != nil {
+			continue
+		}
+		for _, tombName := range entries {
+			tomb, err := readBucketTombstone(ctx, []StorageAPI{disk}, tombName)
+			if err != nil {
+				continue
+			}
+			seen[tombName] = tomb
+		}
+	}
+
+	tombs := make([]bucketTombstone, 0, len(seen))
+	for _, tomb := range seen {
+		tombs = append(tombs, tomb)
+	}
+	return tombs, nil
+}
+
+func readBucketTombstone(ctx context.Context, storageDisks []StorageAPI, tombName string) (bucketTombstone, error) {
+	for _, disk := range storageDisks {
+		if disk == nil {
+			continue
+		}
+		data, err := disk.ReadAll(ctx, minioMetaTmpDeletedBucket, bucketTombstoneMetaPath(tombName))
+		if err != nil {
+			continue
+		}
+		var tomb bucketTombstone
+		if err := json.Unmarshal(data, &tomb); err == nil {
+			return tomb, nil
+		}
+	}
+	return bucketTombstone{}, errVolumeNotFound
+}
+
+// purgeExpiredBucketTombstones permanently removes tombstoned buckets whose
+// retention window has elapsed. Run periodically by pollBucketTombstonePurge,
+// started the first time a bucket is soft-deleted.
+func (er erasureObjects) purgeExpiredBucketTombstones(ctx context.Context) {
+	tombs, err := er.ListDeletedBuckets(ctx)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+
+	now := UTCNow()
+	storageDisks := er.getDisks()
+	for _, tomb := range tombs {
+		if !tomb.expired(now) {
+			continue
+		}
+		for _, disk := range storageDisks {
+			if disk == nil {
+				continue
+			}
+			// TombName is a directory under minioMetaTmpDeletedBucket, not
+			// its own top-level volume, so a recursive Delete - not
+			// DeleteVol - is what actually reclaims the bucket's data.
+			if err := disk.Delete(ctx, minioMetaTmpDeletedBucket, tomb.TombName, DeleteOptions{Recursive: true, Immediate: true}); err != nil && err != errVolumeNotFound {
+				logger.LogIf(ctx, err)
+			}
+		}
+	}
+}
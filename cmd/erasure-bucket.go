@@ -38,6 +38,8 @@ var bucketMetadataOpIgnoredErrs = append(bucketOpIgnoredErrs, errVolumeNotFound)
 func (er erasureObjects) MakeBucketWithLocation(ctx context.Context, bucket string, opts BucketOptions) error {
 	defer NSUpdated(bucket, slashSeparator)
 
+	er.ensureBucketIntentsReconciled()
+
 	// Verify if bucket is valid.
 	if err := s3utils.CheckValidBucketNameStrict(bucket); err != nil {
 		return BucketNameInvalid{Bucket: bucket}
@@ -45,6 +47,15 @@ func (er erasureObjects) MakeBucketWithLocation(ctx context.Context, bucket stri
 
 	storageDisks := er.getDisks()
 
+	// Durably record the intent to create this bucket before touching any
+	// disk, so a crash between here and the commit below is replayed by
+	// reconcileBucketIntents on the next startup instead of leaving a
+	// half-created bucket behind.
+	intent := bucketIntent{ID: mustGetUUID(), Op: bucketIntentCreate, Bucket: bucket, CreatedAt: UTCNow()}
+	if err := writeBucketIntent(ctx, storageDisks, intent); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
 	g := errgroup.WithNErrs(len(storageDisks))
 
 	// Make a volume entry on all underlying storage disks.
@@ -64,9 +75,35 @@ func (er erasureObjects) MakeBucketWithLocation(ctx context.Context, bucket stri
 		}, index)
 	}
 
-	writeQuorum := getWriteQuorum(len(storageDisks))
+	_, writeQuorum := globalBucketQuorumSys.quorumForBucket(ctx, storageDisks, bucket, len(storageDisks))
 	err := reduceWriteQuorumErrs(ctx, g.Wait(), bucketOpIgnoredErrs, writeQuorum)
-	return toObjectErr(err, bucket)
+	if err != nil {
+		if errors.Is(err, errVolumeExists) {
+			// The bucket already existed and this call created nothing, so
+			// there is nothing for the reconciler to complete.
+			clearBucketIntent(ctx, storageDisks, intent.ID)
+			return toObjectErr(err, bucket)
+		}
+		// Leave the intent in place - the reconciler will retry MakeVol on
+		// the remaining disks on next startup rather than us rolling back
+		// disks that did succeed.
+		return toObjectErr(err, bucket)
+	}
+
+	// Committed: the intent has served its purpose.
+	clearBucketIntent(ctx, storageDisks, intent.ID)
+
+	// The bucket now exists; force the next GetBucketInfo to fan out
+	// rather than serve a stale (or absent) cache entry.
+	NotifyBucketMutated(bucket)
+
+	// Persist the requested per-bucket quorum/placement policy, if any, so
+	// subsequent quorum calculations for this bucket honor it. A bucket
+	// created without one simply uses the pool-wide defaults.
+	if err := globalBucketQuorumSys.Set(ctx, storageDisks, bucket, opts.QuorumPolicy); err != nil {
+		logger.LogIf(ctx, err)
+	}
+	return nil
 }
 
 func undoDeleteBucket(storageDisks []StorageAPI, bucket string) {
@@ -121,16 +158,24 @@ func (er erasureObjects) getBucketInfo(ctx context.Context, bucketName string) (
 	// reduce to one error based on read quorum.
 	// `nil` is deliberately passed for ignoredErrs
 	// because these errors were already ignored.
-	readQuorum := getReadQuorum(len(storageDisks))
+	readQuorum, _ := globalBucketQuorumSys.quorumForBucket(ctx, storageDisks, bucketName, len(storageDisks))
 	return BucketInfo{}, reduceReadQuorumErrs(ctx, errs, nil, readQuorum)
 }
 
 // GetBucketInfo - returns BucketInfo for a bucket.
 func (er erasureObjects) GetBucketInfo(ctx context.Context, bucket string) (bi BucketInfo, e error) {
+	er.ensureBucketInfoCachePolling()
+
+	if cached, ok := globalBucketInfoCache.Get(bucket); ok {
+		return cached, nil
+	}
+
 	bucketInfo, err := er.getBucketInfo(ctx, bucket)
 	if err != nil {
 		return bi, toObjectErr(err, bucket)
 	}
+
+	globalBucketInfoCache.Set(bucket, bucketInfo)
 	return bucketInfo, nil
 }
 
@@ -139,8 +184,33 @@ func (er erasureObjects) DeleteBucket(ctx context.Context, bucket string, opts D
 	// Collect if all disks report volume not found.
 	defer NSUpdated(bucket, slashSeparator)
 
+	er.ensureBucketIntentsReconciled()
+
 	storageDisks := er.getDisks()
 
+	if opts.SoftDelete {
+		// Move the bucket into the recycle bin instead of deleting it
+		// outright - it can be brought back with RestoreBucket until the
+		// retention window elapses and purgeExpiredBucketTombstones reaps it.
+		if err := er.softDeleteBucket(ctx, bucket, opts.Retention); err != nil {
+			return err
+		}
+		NotifyBucketMutated(bucket)
+		if err := globalBucketQuorumSys.Delete(ctx, storageDisks, bucket); err != nil {
+			logger.LogIf(ctx, err)
+		}
+		return nil
+	}
+
+	// Durably record the intent to delete this bucket before touching any
+	// disk. A node crash or split-brain between here and the commit below
+	// is replayed deterministically by reconcileBucketIntents rather than
+	// leaving a dangling renamed directory or a half-deleted bucket.
+	intent := bucketIntent{ID: mustGetUUID(), Op: bucketIntentDelete, Bucket: bucket, CreatedAt: UTCNow()}
+	if err := writeBucketIntent(ctx, storageDisks, intent); err != nil {
+		return toObjectErr(err, bucket)
+	}
+
 	g := errgroup.WithNErrs(len(storageDisks))
 
 	for index := range storageDisks {
@@ -160,17 +230,35 @@ func (er erasureObjects) DeleteBucket(ctx context.Context, bucket string, opts D
 		for _, err := range dErrs {
 			if err != nil {
 				undoDeleteBucket(storageDisks, bucket)
+				// The delete was rolled back - the bucket is back, so the
+				// delete-bucket intent no longer describes reality and
+				// must not be replayed by the reconciler.
+				clearBucketIntent(ctx, storageDisks, intent.ID)
 				return toObjectErr(err, bucket)
 			}
 		}
 
+		clearBucketIntent(ctx, storageDisks, intent.ID)
+
+		NotifyBucketMutated(bucket)
+		if derr := globalBucketQuorumSys.Delete(ctx, storageDisks, bucket); derr != nil {
+			logger.LogIf(ctx, derr)
+		}
 		return nil
 	}
 
-	writeQuorum := getWriteQuorum(len(storageDisks))
+	_, writeQuorum := globalBucketQuorumSys.quorumForBucket(ctx, storageDisks, bucket, len(storageDisks))
 	err := reduceWriteQuorumErrs(ctx, dErrs, bucketOpIgnoredErrs, writeQuorum)
 	if err == errErasureWriteQuorum && !opts.NoRecreate {
 		undoDeleteBucket(storageDisks, bucket)
+		// Rolled back: the bucket exists again, so the delete-bucket
+		// intent must not be replayed on the next reconciliation.
+		clearBucketIntent(ctx, storageDisks, intent.ID)
+	}
+	if errors.Is(err, errVolumeNotEmpty) {
+		// Nothing was deleted and the bucket still holds data, so the
+		// delete-bucket intent must not be replayed either.
+		clearBucketIntent(ctx, storageDisks, intent.ID)
 	}
 
 	if err == nil || errors.Is(err, errVolumeNotFound) {
@@ -190,6 +278,19 @@ func (er erasureObjects) DeleteBucket(ctx context.Context, bucket string, opts D
 
 	}
 
+	if err == nil {
+		// Committed: the intent has served its purpose.
+		clearBucketIntent(ctx, storageDisks, intent.ID)
+
+		// Bucket is gone, evict it from the info cache and forget its
+		// quorum policy so a future bucket of the same name starts out
+		// fresh on the pool-wide defaults.
+		NotifyBucketMutated(bucket)
+		if derr := globalBucketQuorumSys.Delete(ctx, storageDisks, bucket); derr != nil {
+			logger.LogIf(ctx, derr)
+		}
+	}
+
 	return toObjectErr(err, bucket)
 }
 